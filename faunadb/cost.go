@@ -0,0 +1,45 @@
+package faunadb
+
+// Cost estimates the reads, writes and index scans a query will perform
+// against FaunaDB. It is a static approximation based solely on the shape
+// of the query: it has no knowledge of collection sizes or whether an
+// index already covers the query, so treat it as a lower bound rather
+// than a prediction of consumed compute.
+type Cost struct {
+	Reads      int
+	Writes     int
+	IndexScans int
+}
+
+// EstimateCost walks expr and tallies the operations it performs.
+func EstimateCost(expr Expr) Cost {
+	c := &costVisitor{}
+	Walk(c, expr)
+	return c.Cost
+}
+
+type costVisitor struct {
+	Cost
+}
+
+func (c *costVisitor) Visit(node Expr) Visitor {
+	obj, ok := node.(unescapedObj)
+	if !ok {
+		return c
+	}
+
+	switch obj.Op() {
+	case "get", "select", "select_all", "paginate", "exists":
+		c.Reads++
+
+	case "create", "create_collection", "create_class", "create_database",
+		"create_index", "create_function", "create_role", "create_key",
+		"update", "replace", "delete", "insert", "remove":
+		c.Writes++
+
+	case "match", "union", "intersection", "difference", "distinct":
+		c.IndexScans++
+	}
+
+	return c
+}
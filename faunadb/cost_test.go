@@ -0,0 +1,18 @@
+package faunadb
+
+import "testing"
+
+func TestEstimateCost(t *testing.T) {
+	expr := unescapedObj{
+		"do": unescapedArr{
+			unescapedObj{"create": StringV("collection"), "params": StringV("p")},
+			unescapedObj{"match": StringV("idx")},
+			unescapedObj{"get": StringV("ref")},
+		},
+	}
+
+	cost := EstimateCost(expr)
+	if cost.Writes != 1 || cost.IndexScans != 1 || cost.Reads != 1 {
+		t.Fatalf("EstimateCost(%v) = %+v, want 1 read, 1 write, 1 index scan", expr, cost)
+	}
+}
@@ -2,6 +2,7 @@ package faunadb
 
 import (
 	"encoding/json"
+	"sort"
 )
 
 /*
@@ -34,6 +35,114 @@ type invalidExpr struct{ err error }
 func (obj unescapedObj) expr()          {}
 func (obj unescapedObj) String() string { byte, _ := json.Marshal(obj); return string(byte) }
 
+// knownOps lists the wire keys that identify an FQL function invocation.
+// Every unescapedObj built by fn1..fn4 for one of these functions carries
+// its name as a key; any remaining keys are that function's named
+// parameters (e.g. "in" on a "let", or "ts" added by an OptionalParameter).
+// "collection" is both an op of its own and the named parameter of "map"
+// and similar functions, so a node can hold two of these keys; Op resolves
+// that case.
+var knownOps = map[string]bool{
+	"let": true, "var": true, "if": true, "do": true, "lambda": true,
+	"call": true, "query": true,
+	"map": true, "foreach": true, "filter": true, "take": true, "drop": true,
+	"prepend": true, "append": true, "is_empty": true, "is_nonempty": true,
+	"create": true, "create_collection": true, "create_class": true,
+	"create_database": true, "create_index": true, "create_function": true,
+	"create_role": true, "create_key": true,
+	"collection": true, "class": true, "database": true, "index": true,
+	"function": true, "role": true,
+	"ref": true, "get": true, "exists": true, "paginate": true, "match": true,
+	"singleton": true,
+	"union": true, "intersection": true, "difference": true, "distinct": true,
+	"select": true, "select_all": true, "update": true, "replace": true,
+	"delete": true, "insert": true, "remove": true, "events": true,
+	"add": true, "multiply": true, "subtract": true, "divide": true,
+	"modulo": true, "lt": true, "lte": true, "gt": true, "gte": true,
+	"equals": true, "and": true, "or": true, "not": true,
+	"count": true, "sum": true, "mean": true, "all": true, "any": true,
+	"to_string": true, "to_number": true, "to_time": true, "to_date": true,
+}
+
+// Op returns the FQL function name this node represents, e.g. "create" or
+// "lambda". It returns "" for plain data objects that are not the result
+// of a query language function.
+//
+// This is a heuristic, not a guarantee: Op recognizes a function call by
+// checking whether any of a node's keys is one of the hardcoded names in
+// knownOps, so a plain data object that happens to have a field named
+// "match", "create", "select", etc. (e.g. Obj{"select": "value"} used as
+// arbitrary seed data) is indistinguishable from a real call to that
+// function. Every Op()-based consumer - Walk's cost/validate dispatch,
+// EstimateCost, Validate - inherits this false-positive risk. Telling the
+// two apart reliably would mean tagging nodes at construction time (fn1..
+// fn4) rather than pattern-matching decoded keys after the fact, which
+// unescapedObj's plain map[string]Expr representation has no room for
+// without also changing what MarshalJSON puts on the wire.
+//
+// When a node holds "collection" and another known key (e.g. {"map": ...,
+// "collection": ...}), the other key is the op; "collection" is only the
+// op of a node that has no other known key.
+func (obj unescapedObj) Op() string {
+	op := ""
+	for _, key := range obj.Keys() {
+		if knownOps[key] && (op == "" || op == "collection") {
+			op = key
+		}
+	}
+	return op
+}
+
+// letBinding is one name/value pair from a Let's "let" key.
+type letBinding struct {
+	Name  string
+	Value Expr
+}
+
+// letBindings returns the ordered (name, value) pairs from a Let node's
+// "let" key. FaunaDB wire-encodes those bindings as an array of
+// single-key objects, specifically to preserve order - a later binding
+// can reference an earlier one by name, and a JSON object (or a Go map)
+// can't guarantee the wire preserves that order. Array elements that
+// aren't single-key objects are skipped.
+func letBindings(obj unescapedObj) []letBinding {
+	arr, ok := obj["let"].(unescapedArr)
+	if !ok {
+		return nil
+	}
+
+	bindings := make([]letBinding, 0, len(arr))
+	for _, item := range arr {
+		pair, ok := item.(unescapedObj)
+		if !ok || len(pair) != 1 {
+			continue
+		}
+		for name, value := range pair {
+			bindings = append(bindings, letBinding{Name: name, Value: value})
+		}
+	}
+	return bindings
+}
+
+// Keys returns the object's keys in a stable, sorted order so that
+// visitors see a deterministic traversal regardless of map iteration
+// order.
+func (obj unescapedObj) Keys() []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Operand returns the child expression stored under key, and whether it
+// was present.
+func (obj unescapedObj) Operand(key string) (Expr, bool) {
+	child, ok := obj[key]
+	return child, ok
+}
+
 func (arr unescapedArr) expr()          {}
 func (arr unescapedArr) String() string { byte, _ := json.Marshal(arr); return string(byte) }
 
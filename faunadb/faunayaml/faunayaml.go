@@ -0,0 +1,125 @@
+// Package faunayaml lets FaunaDB queries and seed data be kept in
+// human-edited YAML fixtures instead of escaped-JSON query bodies.
+//
+// A fixture is just the YAML form of the same wire JSON the query
+// builder produces, so a file describing:
+//
+//	create: { collection: users }
+//	params:
+//	  object:
+//	    data:
+//	      object:
+//	        name: Jane
+//
+// round-trips to the exact query Create(Collection("users"), Obj{"data":
+// Obj{"name": "Jane"}}) would build: "create" and "params" are sibling
+// keys built by fn2, not one nested inside the other.
+package faunayaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/mbraeutig/faunadb-go/faunadb"
+	"gopkg.in/yaml.v2"
+)
+
+// Marshal converts expr to YAML.
+func Marshal(expr faunadb.Expr) ([]byte, error) {
+	return yaml.Marshal(expr)
+}
+
+// Unmarshal parses YAML produced by Marshal (or written by hand) back
+// into an Expr tree.
+func Unmarshal(data []byte, expr *faunadb.Expr) error {
+	var obj unescapedObjAlias
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*expr = obj.Expr
+	return nil
+}
+
+// LoadQueries reads path as a YAML file containing a map of named
+// queries and decodes each entry into an Expr the client can run.
+func LoadQueries(path string) (map[string]faunadb.Expr, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var named map[string]unescapedObjAlias
+	if err := yaml.Unmarshal(raw, &named); err != nil {
+		return nil, err
+	}
+
+	queries := make(map[string]faunadb.Expr, len(named))
+	for name, entry := range named {
+		queries[name] = entry.Expr
+	}
+	return queries, nil
+}
+
+// unescapedObjAlias adapts faunadb.Expr's UnmarshalJSON-based decoding to
+// yaml.v2's unmarshal callback: faunadb.UnmarshalExpr already knows how
+// to turn wire JSON into an Expr, so this type only needs to bridge the
+// generic YAML value into JSON before handing it off.
+type unescapedObjAlias struct {
+	Expr faunadb.Expr
+}
+
+func (u *unescapedObjAlias) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	data, err := yamlValueToJSON(raw)
+	if err != nil {
+		return err
+	}
+
+	expr, err := faunadb.UnmarshalExpr(data)
+	if err != nil {
+		return fmt.Errorf("faunayaml: %w", err)
+	}
+
+	u.Expr = expr
+	return nil
+}
+
+// yamlValueToJSON re-encodes a value decoded by yaml.v2 (which represents
+// nested maps as map[interface{}]interface{}) as JSON, so it can be
+// handed to faunadb.UnmarshalExpr.
+func yamlValueToJSON(v interface{}) ([]byte, error) {
+	return json.Marshal(normalize(v))
+}
+
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[fmt.Sprintf("%v", key)] = normalize(val)
+		}
+		return m
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = normalize(val)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = normalize(val)
+		}
+		return s
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,39 @@
+package faunayaml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadQueriesDecodesSiblingCreateAndParams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.yaml")
+	fixture := []byte(`
+createUser:
+  create: { collection: users }
+  params:
+    object:
+      data:
+        object:
+          name: Jane
+`)
+	if err := os.WriteFile(path, fixture, 0o600); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	queries, err := LoadQueries(path)
+	if err != nil {
+		t.Fatalf("LoadQueries(%q) returned error: %v", path, err)
+	}
+
+	expr, ok := queries["createUser"]
+	if !ok {
+		t.Fatalf("LoadQueries(%q) = %v, missing %q", path, queries, "createUser")
+	}
+
+	got := expr.String()
+	if !strings.Contains(got, `"create"`) || !strings.Contains(got, `"params"`) {
+		t.Fatalf("LoadQueries(%q)[%q] = %s, want sibling \"create\" and \"params\" keys", path, "createUser", got)
+	}
+}
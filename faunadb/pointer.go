@@ -0,0 +1,250 @@
+package faunadb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Lookup, Path and SetAt navigate into an Expr tree without re-serializing
+it, the same way Walk, Rewrite, EstimateCost and Validate operate on it
+without re-parsing wire JSON. They are a prerequisite for query editors,
+diff tools and targeted rewrites that only need to touch one part of a
+large query.
+*/
+
+// LookupReason distinguishes why a Lookup, Path or SetAt call failed.
+type LookupReason int
+
+const (
+	// TokenNotFound means the token named a key or index that does not
+	// exist on the node it was applied to.
+	TokenNotFound LookupReason = iota
+	// NotAddressable means the node at this point in the tree has no
+	// children a pointer can descend into, e.g. a StringV or LongV.
+	NotAddressable
+)
+
+// LookupError reports why Lookup, Path or SetAt could not resolve a
+// pointer against an Expr tree.
+type LookupError struct {
+	Pointer string
+	Token   string
+	Reason  LookupReason
+}
+
+func (e *LookupError) Error() string {
+	switch e.Reason {
+	case NotAddressable:
+		return fmt.Sprintf("faunadb: token %q of pointer %q is not addressable on this node", e.Token, e.Pointer)
+	default:
+		return fmt.Sprintf("faunadb: no such token %q in pointer %q", e.Token, e.Pointer)
+	}
+}
+
+// Lookup resolves an RFC 6901 JSON Pointer against expr, e.g.
+// "/params/object/data/object/name" on a Create. The empty pointer returns
+// expr itself.
+func Lookup(expr Expr, pointer string) (Expr, error) {
+	if pointer == "" {
+		return expr, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, &LookupError{Pointer: pointer, Token: pointer, Reason: NotAddressable}
+	}
+
+	node := expr
+	for _, token := range strings.Split(pointer[1:], "/") {
+		child, err := lookupToken(node, unescapeToken(token))
+		if err != nil {
+			return nil, withPointer(err, pointer)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// Path is a friendlier alternative to Lookup for navigating FQL
+// structures: Path(expr, "create", "collection") is equivalent to
+// Lookup(expr, "/create/collection"), without needing to escape tokens
+// that themselves contain "/" or "~". Tokens resolve exactly as Lookup's
+// do: a Let binding is reached by its position and then its name
+// (Path(expr, "let", "0", "x")), a Lambda parameter by its position
+// (Path(expr, "lambda", "1")), and an Obj field under its "object" key.
+func Path(expr Expr, tokens ...string) (Expr, error) {
+	node := expr
+	escaped := make([]string, 0, len(tokens))
+
+	for _, token := range tokens {
+		escaped = append(escaped, escapeToken(token))
+		child, err := lookupToken(node, token)
+		if err != nil {
+			return nil, withPointer(err, "/"+strings.Join(escaped, "/"))
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// SetAt returns a copy of root with the node at pointer replaced by
+// newExpr, so a targeted edit doesn't require rebuilding the rest of the
+// tree by hand.
+func SetAt(root Expr, pointer string, newExpr Expr) (Expr, error) {
+	if pointer == "" {
+		return newExpr, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, &LookupError{Pointer: pointer, Token: pointer, Reason: NotAddressable}
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = unescapeToken(token)
+	}
+
+	replaced, err := setAtTokens(root, tokens, newExpr)
+	if err != nil {
+		return nil, withPointer(err, pointer)
+	}
+	return replaced, nil
+}
+
+func withPointer(err error, pointer string) error {
+	if le, ok := err.(*LookupError); ok {
+		le.Pointer = pointer
+	}
+	return err
+}
+
+func lookupToken(node Expr, token string) (Expr, error) {
+	switch n := node.(type) {
+	case unescapedObj:
+		child, ok := n[token]
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: TokenNotFound}
+		}
+		return child, nil
+
+	case unescapedArr:
+		idx, ok := arrayIndex(token, len(n))
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: TokenNotFound}
+		}
+		return n[idx], nil
+
+	case Obj:
+		return lookupToken(wrap(n), token)
+
+	case Arr:
+		return lookupToken(wrap(n), token)
+
+	case ObjectV:
+		child, ok := n[token]
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: TokenNotFound}
+		}
+		expr, ok := child.(Expr)
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: NotAddressable}
+		}
+		return expr, nil
+
+	case ArrayV:
+		idx, ok := arrayIndex(token, len(n))
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: TokenNotFound}
+		}
+		expr, ok := n[idx].(Expr)
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: NotAddressable}
+		}
+		return expr, nil
+
+	default:
+		return nil, &LookupError{Token: token, Reason: NotAddressable}
+	}
+}
+
+func setAtTokens(node Expr, tokens []string, newExpr Expr) (Expr, error) {
+	if len(tokens) == 0 {
+		return newExpr, nil
+	}
+
+	token, rest := tokens[0], tokens[1:]
+
+	switch n := node.(type) {
+	case unescapedObj:
+		child, ok := n[token]
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: TokenNotFound}
+		}
+
+		replaced, err := setAtTokens(child, rest, newExpr)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make(unescapedObj, len(n))
+		for key, value := range n {
+			out[key] = value
+		}
+		out[token] = replaced
+		return out, nil
+
+	case unescapedArr:
+		idx, ok := arrayIndex(token, len(n))
+		if !ok {
+			return nil, &LookupError{Token: token, Reason: TokenNotFound}
+		}
+
+		replaced, err := setAtTokens(n[idx], rest, newExpr)
+		if err != nil {
+			return nil, err
+		}
+
+		out := make(unescapedArr, len(n))
+		copy(out, n)
+		out[idx] = replaced
+		return out, nil
+
+	case Obj:
+		return setAtTokens(wrap(n), tokens, newExpr)
+
+	case Arr:
+		return setAtTokens(wrap(n), tokens, newExpr)
+
+	default:
+		return nil, &LookupError{Token: token, Reason: NotAddressable}
+	}
+}
+
+// arrayIndex parses token as an RFC 6901 array index: a sequence of
+// digits with no leading zero (other than "0" itself) and no sign, so
+// "01" and "+1" are rejected even though strconv.Atoi would accept them.
+func arrayIndex(token string, length int) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || idx >= length {
+		return 0, false
+	}
+	return idx, true
+}
+
+func unescapeToken(token string) string {
+	token = strings.ReplaceAll(token, "~1", "/")
+	return strings.ReplaceAll(token, "~0", "~")
+}
+
+func escapeToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
@@ -0,0 +1,67 @@
+package faunadb
+
+import "testing"
+
+func TestLookupResolvesNestedPointer(t *testing.T) {
+	expr := unescapedObj{
+		"create": unescapedObj{"collection": StringV("users")},
+		"params": unescapedObj{"object": unescapedObj{"data": unescapedObj{
+			"object": unescapedObj{"name": StringV("Jane")},
+		}}},
+	}
+
+	got, err := Lookup(expr, "/params/object/data/object/name")
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+	if name, ok := got.(StringV); !ok || string(name) != "Jane" {
+		t.Fatalf("Lookup = %v, want StringV(\"Jane\")", got)
+	}
+}
+
+func TestLookupRejectsNonCanonicalArrayIndex(t *testing.T) {
+	expr := unescapedArr{StringV("a"), StringV("b")}
+
+	for _, pointer := range []string{"/01", "/+1", "/-1"} {
+		if _, err := Lookup(expr, pointer); err == nil {
+			t.Fatalf("Lookup(%v, %q) = nil error, want rejection of non-canonical index", expr, pointer)
+		}
+	}
+
+	got, err := Lookup(expr, "/1")
+	if err != nil {
+		t.Fatalf("Lookup(%v, \"/1\") returned error: %v", expr, err)
+	}
+	if s, ok := got.(StringV); !ok || string(s) != "b" {
+		t.Fatalf("Lookup(%v, \"/1\") = %v, want StringV(\"b\")", expr, got)
+	}
+}
+
+func TestPathNavigatesByName(t *testing.T) {
+	expr := unescapedObj{"create": StringV("ref")}
+
+	got, err := Path(expr, "create")
+	if err != nil {
+		t.Fatalf("Path returned error: %v", err)
+	}
+	if s, ok := got.(StringV); !ok || string(s) != "ref" {
+		t.Fatalf("Path = %v, want StringV(\"ref\")", got)
+	}
+}
+
+func TestSetAtReplacesNode(t *testing.T) {
+	expr := unescapedObj{"create": StringV("old")}
+
+	replaced, err := SetAt(expr, "/create", StringV("new"))
+	if err != nil {
+		t.Fatalf("SetAt returned error: %v", err)
+	}
+
+	obj, ok := replaced.(unescapedObj)
+	if !ok {
+		t.Fatalf("SetAt = %T, want unescapedObj", replaced)
+	}
+	if s, ok := obj["create"].(StringV); !ok || string(s) != "new" {
+		t.Fatalf("SetAt result = %v, want StringV(\"new\")", obj)
+	}
+}
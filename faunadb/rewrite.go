@@ -0,0 +1,44 @@
+package faunadb
+
+// Rewrite returns a copy of root with every node for which fn returns a
+// non-nil replacement substituted in. fn is called bottom-up: a node's
+// children are rewritten before the node itself is passed to fn, so fn
+// can assume any children it sees are already in final form. This is the
+// basis for parameter binding and query templating, e.g. replacing a
+// placeholder Var with a bound value before a query is submitted.
+// Only unescapedObj, unescapedArr, Obj and Arr nodes have their children
+// rewritten; fn sees an ObjectV, ArrayV, RefV or SetRefV only as a whole.
+func Rewrite(root Expr, fn func(Expr) Expr) Expr {
+	rewritten := rewriteChildren(root, fn)
+	if replacement := fn(rewritten); replacement != nil {
+		return replacement
+	}
+	return rewritten
+}
+
+func rewriteChildren(node Expr, fn func(Expr) Expr) Expr {
+	switch n := node.(type) {
+	case unescapedObj:
+		out := make(unescapedObj, len(n))
+		for _, key := range n.Keys() {
+			out[key] = Rewrite(n[key], fn)
+		}
+		return out
+
+	case unescapedArr:
+		out := make(unescapedArr, len(n))
+		for i, child := range n {
+			out[i] = Rewrite(child, fn)
+		}
+		return out
+
+	case Obj:
+		return rewriteChildren(wrap(n), fn)
+
+	case Arr:
+		return rewriteChildren(wrap(n), fn)
+
+	default:
+		return node
+	}
+}
@@ -0,0 +1,34 @@
+package faunadb
+
+import "testing"
+
+func TestRewriteSubstitutesVar(t *testing.T) {
+	expr := unescapedObj{"var": StringV("x")}
+
+	rewritten := Rewrite(expr, func(node Expr) Expr {
+		obj, ok := node.(unescapedObj)
+		if !ok {
+			return nil
+		}
+		if name, ok := obj.varName(); ok && name == "x" {
+			return LongV(42)
+		}
+		return nil
+	})
+
+	got, ok := rewritten.(LongV)
+	if !ok || got != LongV(42) {
+		t.Fatalf("Rewrite(%v) = %v, want LongV(42)", expr, rewritten)
+	}
+}
+
+func TestRewriteLeavesUnmatchedNodesAlone(t *testing.T) {
+	expr := unescapedObj{"get": StringV("ref")}
+
+	rewritten := Rewrite(expr, func(Expr) Expr { return nil })
+
+	obj, ok := rewritten.(unescapedObj)
+	if !ok || obj.Op() != "get" {
+		t.Fatalf("Rewrite(%v) = %v, want unchanged", expr, rewritten)
+	}
+}
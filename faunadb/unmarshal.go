@@ -0,0 +1,413 @@
+package faunadb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/*
+UnmarshalExpr is the inverse of Obj.MarshalJSON and friends: it turns wire
+JSON, such as a query persisted to disk or fetched back from storage, into
+an Expr tree built from the same concrete types the query builder emits.
+This makes a "saved query" workflow possible: persist a query as JSON,
+load it back with UnmarshalExpr, run Walk or Rewrite on it, and resubmit
+it to the client.
+*/
+
+// FnConstructor builds an Expr node from the already-decoded fields of a
+// function object, e.g. decoding {"map": ..., "collection": ...} yields
+// fields{"map": ..., "collection": ...} before the "map" constructor runs.
+// Registering a constructor for a new op lets callers round-trip
+// functions this package doesn't know about without editing core.
+type FnConstructor func(fields map[string]Expr) (Expr, error)
+
+var fnConstructors = map[string]FnConstructor{}
+
+// RegisterFn registers the constructor used to rebuild nodes decoded for
+// the given op, e.g. RegisterFn("lambda", ...). Registering an op that is
+// already registered replaces the previous constructor.
+func RegisterFn(op string, constructor FnConstructor) {
+	fnConstructors[op] = constructor
+}
+
+func init() {
+	asUnescapedObj := func(fields map[string]Expr) (Expr, error) {
+		return unescapedObj(fields), nil
+	}
+	for op := range knownOps {
+		RegisterFn(op, asUnescapedObj)
+	}
+}
+
+// wireTokens maps FaunaDB's special wire keys to the decoder that turns
+// their payload into the matching Value type. "object" is the wrapper
+// wrap/Obj.MarshalJSON put around every plain data object on the wire,
+// not a "@"-prefixed token like the others, but it belongs here for the
+// same reason: decodeObject's op detection below must never see it as
+// the node's function key.
+//
+// This is populated in init rather than as a var literal because several
+// of these decoders (decodeSetRef, decodeWrappedObject) call back into
+// decodeFieldMap/decodeExpr/decodeObject, which read wireTokens - a map
+// literal naming them directly as initializer values creates a spurious
+// package-level initialization cycle even though nothing actually runs
+// until a caller invokes UnmarshalExpr.
+var wireTokens map[string]func(json.RawMessage) (Expr, error)
+
+func init() {
+	wireTokens = map[string]func(json.RawMessage) (Expr, error){
+		"object": decodeWrappedObject,
+		"@ref":   decodeRef,
+		"@set":   decodeSetRef,
+		"@ts":    decodeTime,
+		"@date":  decodeDate,
+		"@bytes": decodeBytes,
+		"@query": decodeQuery,
+	}
+}
+
+// UnmarshalExpr parses data as wire JSON and returns the Expr it encodes.
+func UnmarshalExpr(data []byte) (Expr, error) {
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return decodeExpr(raw)
+}
+
+func decodeExpr(raw json.RawMessage) (Expr, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return decodeObject(obj)
+	}
+
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err == nil {
+		return decodeArray(arr)
+	}
+
+	return decodeLiteral(raw)
+}
+
+func decodeObject(fields map[string]json.RawMessage) (Expr, error) {
+	if len(fields) == 1 {
+		for token, decode := range wireTokens {
+			if raw, ok := fields[token]; ok {
+				return decode(raw)
+			}
+		}
+	}
+
+	keys := make(unescapedObj, len(fields))
+	for key := range fields {
+		keys[key] = nil
+	}
+	op := keys.Op()
+
+	// A key this package doesn't know about can still be an op if a
+	// caller registered a constructor for it with RegisterFn; that's
+	// what lets new functions round-trip without editing knownOps.
+	if op == "" {
+		for key := range fields {
+			if _, ok := fnConstructors[key]; ok {
+				op = key
+				break
+			}
+		}
+	}
+
+	decoded := make(map[string]Expr, len(fields))
+	for key, raw := range fields {
+		child, err := decodeExpr(raw)
+		if err != nil {
+			return nil, fmt.Errorf("faunadb: decoding %q: %w", key, err)
+		}
+		decoded[key] = child
+	}
+
+	if op == "let" {
+		if err := checkLetBindings(unescapedObj(decoded)); err != nil {
+			return nil, err
+		}
+	}
+
+	if op == "" {
+		return unescapedObj(decoded), nil
+	}
+
+	constructor, ok := fnConstructors[op]
+	if !ok {
+		return unescapedObj(decoded), nil
+	}
+
+	return constructor(decoded)
+}
+
+// checkLetBindings rejects a "let" whose bindings declare the same
+// variable name more than once, which would otherwise silently shadow
+// itself depending on evaluation order. Because a Let's bindings are
+// wire-encoded as an array of single-key objects rather than one object
+// with repeated keys, a duplicate name is a distinct array element, not
+// a JSON object key encoding/json would have already silently collapsed
+// - so this can check the decoded letBindings directly.
+func checkLetBindings(obj unescapedObj) error {
+	seen := map[string]bool{}
+	for _, b := range letBindings(obj) {
+		if seen[b.Name] {
+			return fmt.Errorf("faunadb: let binds %q more than once", b.Name)
+		}
+		seen[b.Name] = true
+	}
+	return nil
+}
+
+func decodeArray(raw []json.RawMessage) (Expr, error) {
+	out := make(unescapedArr, len(raw))
+	for i, item := range raw {
+		child, err := decodeExpr(item)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = child
+	}
+	return out, nil
+}
+
+func decodeLiteral(raw json.RawMessage) (Expr, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+
+	switch t := v.(type) {
+	case nil:
+		return NullV{}, nil
+	case bool:
+		return BooleanV(t), nil
+	case float64:
+		if t == float64(int64(t)) {
+			return LongV(int64(t)), nil
+		}
+		return DoubleV(t), nil
+	case string:
+		return StringV(t), nil
+	default:
+		return nil, fmt.Errorf("faunadb: cannot decode %T as an expression", v)
+	}
+}
+
+func decodeFieldMap(raw json.RawMessage) (map[string]Expr, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	decoded := make(map[string]Expr, len(fields))
+	for key, f := range fields {
+		child, err := decodeExpr(f)
+		if err != nil {
+			return nil, err
+		}
+		decoded[key] = child
+	}
+	return decoded, nil
+}
+
+func decodeRef(raw json.RawMessage) (Expr, error) {
+	var wire struct {
+		ID         string          `json:"id"`
+		Collection json.RawMessage `json:"collection"`
+		Database   json.RawMessage `json:"database"`
+	}
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		return nil, err
+	}
+
+	ref := RefV{ID: wire.ID}
+
+	if len(wire.Collection) > 0 {
+		parent, err := decodeExpr(wire.Collection)
+		if err != nil {
+			return nil, err
+		}
+		parentRef, ok := parent.(RefV)
+		if !ok {
+			return nil, fmt.Errorf("faunadb: @ref collection is not a ref")
+		}
+		ref.Collection = &parentRef
+	}
+
+	if len(wire.Database) > 0 {
+		parent, err := decodeExpr(wire.Database)
+		if err != nil {
+			return nil, err
+		}
+		parentRef, ok := parent.(RefV)
+		if !ok {
+			return nil, fmt.Errorf("faunadb: @ref database is not a ref")
+		}
+		ref.Database = &parentRef
+	}
+
+	return ref, nil
+}
+
+func decodeWrappedObject(raw json.RawMessage) (Expr, error) {
+	fields, err := decodeFieldMap(raw)
+	if err != nil {
+		return nil, err
+	}
+	return unescapedObj(fields), nil
+}
+
+func decodeSetRef(raw json.RawMessage) (Expr, error) {
+	fields, err := decodeFieldMap(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]Value, len(fields))
+	for key, expr := range fields {
+		value, ok := expr.(Value)
+		if !ok {
+			return nil, fmt.Errorf("faunadb: @set parameter %q is not a value", key)
+		}
+		params[key] = value
+	}
+
+	return SetRefV{Parameters: params}, nil
+}
+
+func decodeTime(raw json.RawMessage) (Expr, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, err
+	}
+	return TimeV(t), nil
+}
+
+func decodeDate(raw json.RawMessage) (Expr, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, err
+	}
+	return DateV(t), nil
+}
+
+func decodeBytes(raw json.RawMessage) (Expr, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	b, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return BytesV(b), nil
+}
+
+func decodeQuery(raw json.RawMessage) (Expr, error) {
+	return decodeExpr(raw)
+}
+
+// UnmarshalJSON implements json.Unmarshaler for unescapedObj.
+func (obj *unescapedObj) UnmarshalJSON(data []byte) error {
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		return err
+	}
+	decoded, ok := expr.(unescapedObj)
+	if !ok {
+		return fmt.Errorf("faunadb: expected an object, got %T", expr)
+	}
+	*obj = decoded
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for unescapedArr.
+func (arr *unescapedArr) UnmarshalJSON(data []byte) error {
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		return err
+	}
+	decoded, ok := expr.(unescapedArr)
+	if !ok {
+		return fmt.Errorf("faunadb: expected an array, got %T", expr)
+	}
+	*arr = decoded
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Obj, unwrapping wire JSON
+// back into plain Go values rather than the internal Expr types.
+func (obj *Obj) UnmarshalJSON(data []byte) error {
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		return err
+	}
+	unwrapped, ok := unwrapExpr(expr).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("faunadb: expected an object, got %T", expr)
+	}
+	*obj = unwrapped
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Arr, unwrapping wire JSON
+// back into plain Go values rather than the internal Expr types.
+func (arr *Arr) UnmarshalJSON(data []byte) error {
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		return err
+	}
+	unwrapped, ok := unwrapExpr(expr).([]interface{})
+	if !ok {
+		return fmt.Errorf("faunadb: expected an array, got %T", expr)
+	}
+	*arr = unwrapped
+	return nil
+}
+
+// unwrapExpr turns a decoded Expr tree back into the plain map/slice/
+// scalar shape Obj and Arr expect, mirroring what wrap does in reverse.
+func unwrapExpr(expr Expr) interface{} {
+	switch n := expr.(type) {
+	case unescapedObj:
+		m := make(map[string]interface{}, len(n))
+		for key, child := range n {
+			m[key] = unwrapExpr(child)
+		}
+		return m
+
+	case unescapedArr:
+		s := make([]interface{}, len(n))
+		for i, child := range n {
+			s[i] = unwrapExpr(child)
+		}
+		return s
+
+	case StringV:
+		return string(n)
+	case LongV:
+		return int64(n)
+	case DoubleV:
+		return float64(n)
+	case BooleanV:
+		return bool(n)
+	case NullV:
+		return nil
+
+	default:
+		return n
+	}
+}
@@ -0,0 +1,73 @@
+package faunadb
+
+import "testing"
+
+func TestUnmarshalExprRoundTripsLet(t *testing.T) {
+	data := []byte(`{"let":[{"x":1}],"in":{"var":"x"}}`)
+
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		t.Fatalf("UnmarshalExpr(%s) returned error: %v", data, err)
+	}
+
+	obj, ok := expr.(unescapedObj)
+	if !ok || obj.Op() != "let" {
+		t.Fatalf("UnmarshalExpr(%s) = %v, want a let expression", data, expr)
+	}
+
+	bindings := letBindings(obj)
+	if len(bindings) != 1 || bindings[0].Name != "x" {
+		t.Fatalf("letBindings(%v) = %v, want a single binding named %q", obj, bindings, "x")
+	}
+}
+
+func TestUnmarshalExprRejectsDuplicateLetBinding(t *testing.T) {
+	data := []byte(`{"let":[{"x":1},{"x":2}],"in":{"var":"x"}}`)
+
+	if _, err := UnmarshalExpr(data); err == nil {
+		t.Fatalf("UnmarshalExpr(%s) = nil error, want a duplicate binding error", data)
+	}
+}
+
+func TestUnmarshalExprRoundTripsObjLiteral(t *testing.T) {
+	data, err := Obj{"name": "Jane"}.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Obj.MarshalJSON() returned error: %v", err)
+	}
+
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		t.Fatalf("UnmarshalExpr(%s) returned error: %v", data, err)
+	}
+
+	obj, ok := expr.(unescapedObj)
+	if !ok {
+		t.Fatalf("UnmarshalExpr(%s) = %T, want unescapedObj", data, expr)
+	}
+	if _, ok := obj["object"]; ok {
+		t.Fatalf("UnmarshalExpr(%s) = %v, spurious %q wrapper survived decoding", data, obj, "object")
+	}
+	if name, ok := obj["name"].(StringV); !ok || string(name) != "Jane" {
+		t.Fatalf("UnmarshalExpr(%s) = %v, want {%q: StringV(%q)}", data, obj, "name", "Jane")
+	}
+}
+
+func TestRegisterFnHandlesUnknownOp(t *testing.T) {
+	RegisterFn("custom_op", func(fields map[string]Expr) (Expr, error) {
+		return unescapedObj(fields), nil
+	})
+
+	data := []byte(`{"custom_op":{"a":1}}`)
+	expr, err := UnmarshalExpr(data)
+	if err != nil {
+		t.Fatalf("UnmarshalExpr(%s) returned error: %v", data, err)
+	}
+
+	obj, ok := expr.(unescapedObj)
+	if !ok {
+		t.Fatalf("UnmarshalExpr(%s) = %v, want unescapedObj", data, expr)
+	}
+	if _, ok := obj["custom_op"]; !ok {
+		t.Fatalf("UnmarshalExpr(%s) = %v, want %q recognized without editing knownOps", data, obj, "custom_op")
+	}
+}
@@ -0,0 +1,141 @@
+package faunadb
+
+import "fmt"
+
+// ValidationError describes a problem Validate found in a query before it
+// was sent to the server.
+type ValidationError struct {
+	Op      string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("faunadb: invalid %q expression: %s", e.Op, e.Message)
+}
+
+// Validate walks expr looking for mistakes that are cheap to catch
+// locally rather than learning about them from a failed round-trip to the
+// server: a Lambda or Let binding a Var never declares, and Paginate
+// wrapped around something that isn't a set. It returns every problem it
+// finds, or nil if expr looks sound.
+func Validate(expr Expr) []error {
+	v := &validator{bound: map[string]bool{}}
+	Walk(v, expr)
+	return v.errs
+}
+
+type validator struct {
+	bound map[string]bool
+	errs  []error
+}
+
+func (v *validator) Visit(node Expr) Visitor {
+	obj, ok := node.(unescapedObj)
+	if !ok {
+		return v
+	}
+
+	switch obj.Op() {
+	case "let":
+		return v.visitLet(obj)
+
+	case "lambda":
+		return v.visitLambda(obj)
+
+	case "var":
+		if name, ok := obj.varName(); ok && !v.bound[name] {
+			v.errs = append(v.errs, &ValidationError{
+				Op:      "var",
+				Message: fmt.Sprintf("%q is not bound by an enclosing Let or Lambda", name),
+			})
+		}
+
+	case "paginate":
+		if set, ok := obj.Operand("paginate"); ok && !looksLikeSet(set) {
+			v.errs = append(v.errs, &ValidationError{
+				Op:      "paginate",
+				Message: "argument is not a set (Match, Union, Intersection, ... or a set literal)",
+			})
+		}
+	}
+
+	return v
+}
+
+// visitLet binds the names a Let introduces, in binding order so a later
+// binding's Walk sees earlier ones already bound, then walks its "in"
+// body before removing them so sibling expressions don't see them.
+func (v *validator) visitLet(obj unescapedObj) Visitor {
+	bindings := letBindings(obj)
+
+	for _, b := range bindings {
+		v.bound[b.Name] = true
+		Walk(v, b.Value)
+	}
+
+	if body, ok := obj["in"]; ok {
+		Walk(v, body)
+	}
+
+	for _, b := range bindings {
+		delete(v.bound, b.Name)
+	}
+
+	return nil
+}
+
+// visitLambda binds a lambda's parameter name(s) before walking its body.
+func (v *validator) visitLambda(obj unescapedObj) Visitor {
+	var names []string
+	switch params := obj["lambda"].(type) {
+	case StringV:
+		names = append(names, string(params))
+	case unescapedArr:
+		for _, p := range params {
+			if s, ok := p.(StringV); ok {
+				names = append(names, string(s))
+			}
+		}
+	}
+
+	for _, name := range names {
+		v.bound[name] = true
+	}
+
+	if body, ok := obj["expr"]; ok {
+		Walk(v, body)
+	}
+
+	for _, name := range names {
+		delete(v.bound, name)
+	}
+
+	return nil
+}
+
+func (obj unescapedObj) varName() (string, bool) {
+	child, ok := obj["var"]
+	if !ok {
+		return "", false
+	}
+	name, ok := child.(StringV)
+	return string(name), ok
+}
+
+// looksLikeSet reports whether expr is the kind of node Paginate expects:
+// a set-returning function call, or an already-resolved SetRefV.
+func looksLikeSet(expr Expr) bool {
+	switch n := expr.(type) {
+	case SetRefV:
+		return true
+
+	case unescapedObj:
+		switch n.Op() {
+		case "match", "union", "intersection", "difference", "distinct",
+			"paginate", "events", "singleton":
+			return true
+		}
+	}
+
+	return false
+}
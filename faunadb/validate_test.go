@@ -0,0 +1,58 @@
+package faunadb
+
+import "testing"
+
+func TestValidateLetBindingIsBound(t *testing.T) {
+	expr := unescapedObj{
+		"let": unescapedArr{unescapedObj{"x": LongV(1)}},
+		"in":  unescapedObj{"var": StringV("x")},
+	}
+
+	if errs := Validate(expr); len(errs) != 0 {
+		t.Fatalf("Validate(%v) = %v, want no errors", expr, errs)
+	}
+}
+
+func TestValidateLaterLetBindingSeesEarlierOne(t *testing.T) {
+	expr := unescapedObj{
+		"let": unescapedArr{
+			unescapedObj{"x": LongV(1)},
+			unescapedObj{"y": unescapedObj{"var": StringV("x")}},
+		},
+		"in": unescapedObj{"var": StringV("y")},
+	}
+
+	if errs := Validate(expr); len(errs) != 0 {
+		t.Fatalf("Validate(%v) = %v, want no errors", expr, errs)
+	}
+}
+
+func TestValidateVarNotBound(t *testing.T) {
+	expr := unescapedObj{"var": StringV("y")}
+
+	if errs := Validate(expr); len(errs) != 1 {
+		t.Fatalf("Validate(%v) = %v, want exactly one error", expr, errs)
+	}
+}
+
+func TestValidateLetBindingGoesOutOfScope(t *testing.T) {
+	letExpr := unescapedObj{
+		"let": unescapedArr{unescapedObj{"x": LongV(1)}},
+		"in":  StringV("noop"),
+	}
+	outer := unescapedObj{
+		"do": unescapedArr{letExpr, unescapedObj{"var": StringV("x")}},
+	}
+
+	if errs := Validate(outer); len(errs) != 1 {
+		t.Fatalf("Validate(%v) = %v, want exactly one error for %q used outside its Let", outer, errs, "x")
+	}
+}
+
+func TestValidatePaginateNonSet(t *testing.T) {
+	expr := unescapedObj{"paginate": StringV("not-a-set")}
+
+	if errs := Validate(expr); len(errs) != 1 {
+		t.Fatalf("Validate(%v) = %v, want exactly one paginate error", expr, errs)
+	}
+}
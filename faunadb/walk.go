@@ -0,0 +1,86 @@
+package faunadb
+
+import "sort"
+
+/*
+Visitor and Walk add a semantic layer on top of Expr: something that can
+tell what operation a node represents (Op) and reach its operands, rather
+than re-parsing the wire JSON that MarshalJSON produces. This mirrors the
+visitor pattern used by go/ast and similar ASTs, and is the foundation for
+Rewrite, EstimateCost and Validate.
+*/
+
+// Visitor visits nodes of an Expr tree. Walk calls Visit for every node it
+// encounters; the Visitor it returns is used to visit that node's
+// children, or the subtree is skipped entirely if Visit returns nil.
+type Visitor interface {
+	Visit(node Expr) Visitor
+}
+
+// Walk traverses node and its children in depth-first order, calling
+// v.Visit for each one it encounters.
+func Walk(v Visitor, node Expr) {
+	if v == nil || node == nil {
+		return
+	}
+
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case unescapedObj:
+		for _, key := range n.Keys() {
+			Walk(v, n[key])
+		}
+
+	case unescapedArr:
+		for _, child := range n {
+			Walk(v, child)
+		}
+
+	case Obj:
+		Walk(v, wrap(n))
+
+	case Arr:
+		Walk(v, wrap(n))
+
+	case RefV:
+		if n.Collection != nil {
+			Walk(v, n.Collection)
+		}
+		if n.Database != nil {
+			Walk(v, n.Database)
+		}
+
+	case ObjectV:
+		for _, key := range sortedValueKeys(n) {
+			if child, ok := n[key].(Expr); ok {
+				Walk(v, child)
+			}
+		}
+
+	case ArrayV:
+		for _, value := range n {
+			if child, ok := value.(Expr); ok {
+				Walk(v, child)
+			}
+		}
+
+	case SetRefV:
+		for _, key := range sortedValueKeys(n.Parameters) {
+			if child, ok := n.Parameters[key].(Expr); ok {
+				Walk(v, child)
+			}
+		}
+	}
+}
+
+func sortedValueKeys(m map[string]Value) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package faunadb
+
+import "testing"
+
+type collectOps struct {
+	ops []string
+}
+
+func (c *collectOps) Visit(node Expr) Visitor {
+	if obj, ok := node.(unescapedObj); ok {
+		if op := obj.Op(); op != "" {
+			c.ops = append(c.ops, op)
+		}
+	}
+	return c
+}
+
+func TestWalkVisitsNestedOps(t *testing.T) {
+	expr := unescapedObj{
+		"do": unescapedArr{
+			unescapedObj{"create": StringV("collection")},
+			unescapedObj{"get": StringV("ref")},
+		},
+	}
+
+	c := &collectOps{}
+	Walk(c, expr)
+
+	if len(c.ops) != 3 {
+		t.Fatalf("Walk visited ops %v, want 3 (do, create, get)", c.ops)
+	}
+}
+
+func TestWalkStopsWhenVisitReturnsNil(t *testing.T) {
+	calls := 0
+	v := visitFunc(func(node Expr) Visitor {
+		calls++
+		return nil
+	})
+
+	Walk(v, unescapedObj{"do": unescapedArr{unescapedObj{"get": StringV("ref")}}})
+
+	if calls != 1 {
+		t.Fatalf("Walk called Visit %d times, want 1 (children skipped)", calls)
+	}
+}
+
+type visitFunc func(Expr) Visitor
+
+func (f visitFunc) Visit(node Expr) Visitor { return f(node) }
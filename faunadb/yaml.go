@@ -0,0 +1,193 @@
+package faunadb
+
+import "encoding/json"
+
+/*
+MarshalYAML/UnmarshalYAML let Obj, Arr and the query types serialize to
+YAML as well as JSON, so a FaunaDB query or a fixture of seed data can be
+kept in a `.yaml` file (much more readable than the escaped-JSON form) and
+loaded at startup. Both directions route through the same MarshalJSON/
+UnmarshalJSON (and therefore the same wrap/unwrap machinery) that the wire
+protocol uses, so a YAML fixture round-trips to the exact JSON the query
+builder would otherwise produce; this file only adapts the edges to
+gopkg.in/yaml.v2's Marshaler/Unmarshaler interfaces.
+*/
+
+// MarshalYAML implements yaml.Marshaler for Obj.
+func (obj Obj) MarshalYAML() (interface{}, error) {
+	return marshalJSONAsYAML(obj)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for Obj.
+func (obj *Obj) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	data, err := unmarshalYAMLAsJSON(unmarshal)
+	if err != nil {
+		return err
+	}
+	return obj.UnmarshalJSON(data)
+}
+
+// MarshalYAML implements yaml.Marshaler for Arr.
+func (arr Arr) MarshalYAML() (interface{}, error) {
+	return marshalJSONAsYAML(arr)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for Arr.
+func (arr *Arr) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	data, err := unmarshalYAMLAsJSON(unmarshal)
+	if err != nil {
+		return err
+	}
+	return arr.UnmarshalJSON(data)
+}
+
+// MarshalYAML implements yaml.Marshaler for unescapedObj.
+func (obj unescapedObj) MarshalYAML() (interface{}, error) {
+	return jsonValueToYAML(obj)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for unescapedObj.
+func (obj *unescapedObj) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	data, err := unmarshalYAMLAsJSON(unmarshal)
+	if err != nil {
+		return err
+	}
+	return obj.UnmarshalJSON(data)
+}
+
+// MarshalYAML implements yaml.Marshaler for unescapedArr.
+func (arr unescapedArr) MarshalYAML() (interface{}, error) {
+	return jsonValueToYAML(arr)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for unescapedArr.
+func (arr *unescapedArr) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	data, err := unmarshalYAMLAsJSON(unmarshal)
+	if err != nil {
+		return err
+	}
+	return arr.UnmarshalJSON(data)
+}
+
+// ValueYAML is the shared MarshalYAML implementation every concrete Value
+// type (StringV, LongV, RefV, SetRefV, ...) delegates to, so each only
+// needs a one-line `func (v T) MarshalYAML() (interface{}, error) {
+// return ValueYAML(v) }` rather than reimplementing the JSON bridge. It
+// takes v as interface{} rather than json.Marshaler: some Value types
+// (RefV, SetRefV, DateV, TimeV, BytesV, NullV, ObjectV) implement
+// MarshalJSON themselves, but others (StringV, LongV, DoubleV, BooleanV,
+// ArrayV) are plain aliases that encoding/json encodes by reflection,
+// with no MarshalJSON method to require.
+func ValueYAML(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonValueToYAML(json.RawMessage(data))
+}
+
+// MarshalYAML implements yaml.Marshaler for StringV.
+func (v StringV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for LongV.
+func (v LongV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for DoubleV.
+func (v DoubleV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for BooleanV.
+func (v BooleanV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for NullV.
+func (v NullV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for TimeV.
+func (v TimeV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for DateV.
+func (v DateV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for BytesV.
+func (v BytesV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for RefV.
+func (v RefV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for SetRefV.
+func (v SetRefV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for ObjectV.
+func (v ObjectV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+// MarshalYAML implements yaml.Marshaler for ArrayV.
+func (v ArrayV) MarshalYAML() (interface{}, error) { return ValueYAML(v) }
+
+func marshalJSONAsYAML(v json.Marshaler) (interface{}, error) {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return jsonValueToYAML(json.RawMessage(data))
+}
+
+// jsonValueToYAML marshals v to JSON and back into a plain interface{}
+// (map[string]interface{} / []interface{} / scalars) so that yaml.v2
+// encodes readable YAML instead of an embedded JSON string.
+func jsonValueToYAML(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// unmarshalYAMLAsJSON decodes the generic YAML value unmarshal produces
+// into plain Go data (normalizing yaml.v2's map[interface{}]interface{})
+// and re-encodes it as JSON so it can be fed to an UnmarshalJSON method.
+func unmarshalYAMLAsJSON(unmarshal func(interface{}) error) ([]byte, error) {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return nil, err
+	}
+	return json.Marshal(normalizeYAMLValue(raw))
+}
+
+func normalizeYAMLValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[toYAMLKey(key)] = normalizeYAMLValue(val)
+		}
+		return m
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = normalizeYAMLValue(val)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = normalizeYAMLValue(val)
+		}
+		return s
+
+	default:
+		return v
+	}
+}
+
+func toYAMLKey(key interface{}) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(key)
+	return string(data)
+}
@@ -0,0 +1,40 @@
+package faunadb
+
+import "testing"
+
+func TestObjMarshalYAMLWrapsUnderObjectKey(t *testing.T) {
+	obj := Obj{"data": Obj{"name": "Jane"}}
+
+	data, err := obj.MarshalYAML()
+	if err != nil {
+		t.Fatalf("MarshalYAML() returned error: %v", err)
+	}
+
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("MarshalYAML() = %T, want map[string]interface{}", data)
+	}
+	if _, ok := m["object"]; !ok {
+		t.Fatalf("MarshalYAML() = %v, want wrapped under %q", m, "object")
+	}
+}
+
+func TestStringVMarshalYAMLDelegatesToValueYAML(t *testing.T) {
+	data, err := StringV("hi").MarshalYAML()
+	if err != nil {
+		t.Fatalf("MarshalYAML() returned error: %v", err)
+	}
+	if data != "hi" {
+		t.Fatalf("MarshalYAML() = %v, want %q", data, "hi")
+	}
+}
+
+func TestLongVMarshalYAMLDelegatesToValueYAML(t *testing.T) {
+	data, err := LongV(42).MarshalYAML()
+	if err != nil {
+		t.Fatalf("MarshalYAML() returned error: %v", err)
+	}
+	if n, ok := data.(float64); !ok || n != 42 {
+		t.Fatalf("MarshalYAML() = %v, want 42", data)
+	}
+}